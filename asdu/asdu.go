@@ -2,8 +2,10 @@
 package asdu
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"log"
 	"math/bits"
 	"time"
 )
@@ -84,8 +86,8 @@ func (this *Params) IdentifierSize() int {
 type Identifier struct {
 	// type identification, information content
 	Type TypeID
-	// Variable is variable structure qualifier
-	Variable VariableStruct
+	// Variable is the variable structure qualifier: SQ flag plus count.
+	Variable VarStructQual
 	// cause of transmission submission category
 	Coa CauseOfTransmission
 	// Originator Address [1, 255] or 0 for the default.
@@ -126,8 +128,25 @@ func NewASDU(p *Params, identifier Identifier) *ASDU {
 	return a
 }
 
-// AppendInfoObjAddr appends an information object address to Info.
+// AppendInfoObjAddr appends an information object address to Info and
+// advances the variable structure qualifier's count by one. In sequence
+// mode (SQ set) that one count covers only the first element after the
+// address; the further elements of the sequence are not counted by it,
+// nor by any other exported method. Use it for information
+// objects that carry nothing but an address and a single fixed-size
+// element; composite objects with several fields (see the file transfer
+// Append* helpers) build on appendInfoObjAddrNoCount instead and count
+// the whole object once.
 func (u *ASDU) AppendInfoObjAddr(addr InfoObjAddr) error {
+	if err := u.appendInfoObjAddrNoCount(addr); err != nil {
+		return err
+	}
+	return u.Variable.incCount()
+}
+
+// appendInfoObjAddrNoCount appends an information object address without
+// touching the variable structure qualifier's count.
+func (u *ASDU) appendInfoObjAddrNoCount(addr InfoObjAddr) error {
 	switch u.InfoObjAddrSize {
 	case 1:
 		if addr > 255 {
@@ -171,122 +190,238 @@ func (this *ASDU) ParseInfoObjAddr(buf []byte) (InfoObjAddr, error) {
 	return 0, errParam
 }
 
-// IncVariableNumber See companion standard 101, subclause 7.2.2.
-func (this *ASDU) IncVariableNumber(n int) error {
-	if n += int(this.Variable.Number); n >= 128 {
-		return errInfoObjIndexFit
+// infoObjCount derives the number of information objects from the current
+// length of InfoObj, the address size and the fixed element size objSize.
+func (this *ASDU) infoObjCount(objSize int) (int, error) {
+	addrSize := this.InfoObjAddrSize
+	if this.Variable.Sequence() {
+		if len(this.InfoObj) < addrSize {
+			return 0, errInfoObjIndexFit
+		}
+		return (len(this.InfoObj) - addrSize) / objSize, nil
 	}
-	this.Variable.Number = byte(n)
+	return len(this.InfoObj) / (addrSize + objSize), nil
+}
+
+// IncVariableNumber used to bump the variable structure qualifier's count
+// by hand.
+//
+// Deprecated: AppendInfoObjAddr and the higher-level append helpers now
+// maintain the count themselves, one per appended address. This is a
+// no-op kept for source compatibility and logs so remaining call sites
+// can be found; it does not count the further elements of a sequence
+// (SQ set) either.
+func (this *ASDU) IncVariableNumber(n int) error {
+	log.Println("asdu: ASDU.IncVariableNumber is deprecated, counting is automatic")
 	return nil
 }
 
 // Respond returns a new "responding" ASDU which addresses "initiating" u.
-//func (u *ASDU) Respond(t TypeID, c Cause) *ASDU {
-//	return NewASDU(u.Params, Identifier{
-//		CommonAddr: u.CommonAddr,
-//		OrigAddr:   u.OrigAddr,
-//		Type:       t,
-//		Cause:      c | u.Cause&TestFlag,
-//	})
-//}
-
-// Reply returns a new "responding" ASDU which addresses "initiating" u with a copy of Info.
-//func (u *ASDU) Reply(c Cause,addr CommonAddr) *ASDU {
-//	r := NewASDU(u.Params, u.Identifier)
-//	r.Cause = c | u.Cause&TestFlag
-//	r.InfoSeq = u.InfoSequence {
-//	r.InfoObj = append(r.InfoObj, u.InfoObj...)
-////	return r
-//}
-
-//// String returns a full description.
-//func (u *ASDU) String() string {
-//	dataSize, err := GetInfoObjSize(u.Type)
-//	if err != nil {
-//		if !u.InfoSeq {
-//			return fmt.Sprintf("%s: %#x", u.Identifier, u.InfoObj)
-//		}
-//		return fmt.Sprintf("%s seq: %#x", u.Identifier, u.InfoObj)
-//	}
-//
-//	end := len(u.InfoObj)
-//	addrSize := u.InfoObjAddrSize
-//	if end < addrSize {
-//		if !u.InfoSeq {
-//			return fmt.Sprintf("%s: %#x <EOF>", u.Identifier, u.InfoObj)
-//		}
-//		return fmt.Sprintf("%s seq: %#x <EOF>", u.Identifier, u.InfoObj)
-//	}
-//	addr := u.ParseInfoObjAddr(u.InfoObj)
-//
-//	buf := bytes.NewBufferString(u.Identifier.String())
-//
-//	for i := addrSize; ; {
-//		start := i
-//		i += dataSize
-//		if i > end {
-//			fmt.Fprintf(buf, " %d:%#x <EOF>", addr, u.InfoObj[start:])
-//			break
-//		}
-//		fmt.Fprintf(buf, " %d:%#x", addr, u.InfoObj[start:i])
-//		if i == end {
-//			break
-//		}
-//
-//		if u.InfoSeq {
-//			addr++
-//		} else {
-//			start = i
-//			i += addrSize
-//			if i > end {
-//				fmt.Fprintf(buf, " %#x <EOF>", u.InfoObj[start:i])
-//				break
-//			}
-//			addr = u.ParseInfoObjAddr(u.InfoObj[start:])
-//		}
-//	}
-//
-//	return buf.String()
-//}
+// It carries u's CommonAddr and OrigAddr and preserves the TestFlag bit
+// of u's cause of transmission.
+func (u *ASDU) Respond(t TypeID, c Cause) *ASDU {
+	return NewASDU(u.Params, Identifier{
+		CommonAddr: u.CommonAddr,
+		OrigAddr:   u.OrigAddr,
+		Type:       t,
+		Coa:        CauseOfTransmission{Cause: c | u.Coa.Cause&TestFlag},
+	})
+}
 
-// MarshalBinary honors the encoding.BinaryMarshaler interface.
-func (this *ASDU) MarshalBinary() (data []byte, err error) {
+// Reply returns a new "responding" ASDU which addresses "initiating" u
+// with a deep copy of InfoObj, preserving the TestFlag bit of u's cause
+// of transmission.
+func (u *ASDU) Reply(c Cause) *ASDU {
+	r := NewASDU(u.Params, u.Identifier)
+	r.Coa = CauseOfTransmission{Cause: c | u.Coa.Cause&TestFlag}
+	r.InfoObj = append(r.InfoObj, u.InfoObj...)
+	return r
+}
+
+// InfoObject is a decoded information object: its address and the raw,
+// fixed-size payload bytes as reported by GetInfoObjSize.
+type InfoObject struct {
+	Addr InfoObjAddr
+	Raw  []byte
+}
+
+// Objects decodes InfoObj into a slice of InfoObject, honoring the SQ
+// flag of the variable structure qualifier: in sequence mode addresses
+// are derived by incrementing from the single leading address, otherwise
+// each object carries its own address.
+func (u *ASDU) Objects() ([]InfoObject, error) {
+	objSize, err := GetInfoObjSize(u.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	addrSize := u.InfoObjAddrSize
+	end := len(u.InfoObj)
+	objs := make([]InfoObject, 0, u.Variable.Count())
+
+	if u.Variable.Sequence() {
+		addr, err := u.ParseInfoObjAddr(u.InfoObj)
+		if err != nil {
+			return nil, err
+		}
+		for i := addrSize; ; addr++ {
+			start := i
+			i += objSize
+			if i > end {
+				return nil, io.EOF
+			}
+			objs = append(objs, InfoObject{Addr: addr, Raw: u.InfoObj[start:i]})
+			if i == end {
+				return objs, nil
+			}
+		}
+	}
+
+	for i := 0; ; {
+		start := i
+		i += addrSize
+		if i > end {
+			return nil, io.EOF
+		}
+		addr, err := u.ParseInfoObjAddr(u.InfoObj[start:i])
+		if err != nil {
+			return nil, err
+		}
+		start = i
+		i += objSize
+		if i > end {
+			return nil, io.EOF
+		}
+		objs = append(objs, InfoObject{Addr: addr, Raw: u.InfoObj[start:i]})
+		if i == end {
+			return objs, nil
+		}
+	}
+}
+
+// String returns a stable, human-readable dump: the identifier followed
+// by "addr:hex" pairs for each information object, with a trailing
+// "<EOF>" marker where InfoObj ends partway through an address or an
+// element. The count in the variable structure qualifier is not
+// consulted.
+func (u *ASDU) String() string {
+	dataSize, err := GetInfoObjSize(u.Type)
+	if err != nil {
+		return fmt.Sprintf("%s: %#x", u.Identifier, u.InfoObj)
+	}
+
+	end := len(u.InfoObj)
+	addrSize := u.InfoObjAddrSize
+	if end < addrSize {
+		return fmt.Sprintf("%s: %#x <EOF>", u.Identifier, u.InfoObj)
+	}
+	addr, err := u.ParseInfoObjAddr(u.InfoObj)
+	if err != nil {
+		return fmt.Sprintf("%s: %#x <EOF>", u.Identifier, u.InfoObj)
+	}
+
+	buf := bytes.NewBufferString(u.Identifier.String())
+
+	for i := addrSize; ; {
+		start := i
+		i += dataSize
+		if i > end {
+			fmt.Fprintf(buf, " %d:%#x <EOF>", addr, u.InfoObj[start:])
+			break
+		}
+		fmt.Fprintf(buf, " %d:%#x", addr, u.InfoObj[start:i])
+		if i == end {
+			break
+		}
+
+		if u.Variable.Sequence() {
+			addr++
+		} else {
+			start = i
+			i += addrSize
+			if i > end {
+				fmt.Fprintf(buf, " %#x <EOF>", u.InfoObj[start:])
+				break
+			}
+			addr, err = u.ParseInfoObjAddr(u.InfoObj[start:])
+			if err != nil {
+				buf.WriteString(" <EOF>")
+				break
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// MarshalInto writes the wire encoding of u into dst, which must be at
+// least IdentifierSize()+len(InfoObj) bytes, and returns the number of
+// bytes written. It lets callers such as the CS104 transports encode
+// straight into their own send buffer instead of going through the
+// bootstrap array, avoiding an allocation per message.
+func (this *ASDU) MarshalInto(dst []byte) (n int, err error) {
 	switch {
 	case this.Coa.Cause == Unused:
-		return nil, errCauseZero
+		return 0, errCauseZero
 	case !(this.CauseSize == 1 || this.CauseSize == 2):
-		return nil, errParam
+		return 0, errParam
 	case this.CauseSize == 1 && this.OrigAddr != 0:
-		return nil, errOriginAddrFit
+		return 0, errOriginAddrFit
 	case this.CommonAddr == InvalidCommonAddr:
-		return nil, errCommonAddrZero
+		return 0, errCommonAddrZero
 	case !(this.CommonAddrSize == 1 || this.CommonAddrSize == 2):
-		return nil, errParam
+		return 0, errParam
 	case this.CommonAddrSize == 1 && this.CommonAddr != GlobalCommonAddr && this.CommonAddr >= 255:
-		return nil, errParam
+		return 0, errParam
+	}
+
+	if objSize, err := GetInfoObjSize(this.Type); err == nil {
+		wantCount, err := this.infoObjCount(objSize)
+		if err != nil {
+			return 0, err
+		}
+		if wantCount != this.Variable.Count() {
+			return 0, errVarStructCountFit
+		}
+	}
+
+	n = this.IdentifierSize() + len(this.InfoObj)
+	if n > len(dst) {
+		return 0, io.ErrShortBuffer
 	}
 
-	raw := this.bootstrap[:(this.IdentifierSize() + len(this.InfoObj))]
-	raw[0] = byte(this.Type)
-	raw[1] = this.Variable.Value()
-	raw[2] = byte(this.Coa.Value())
+	dst[0] = byte(this.Type)
+	dst[1] = this.Variable.Value()
+	dst[2] = byte(this.Coa.Value())
 	offset := 3
 	if this.CauseSize == 2 {
-		raw[offset] = byte(this.OrigAddr)
+		dst[offset] = byte(this.OrigAddr)
 		offset++
 	}
 	if this.CommonAddrSize == 1 {
 		if this.CommonAddr == GlobalCommonAddr {
-			raw[offset] = 255
+			dst[offset] = 255
 		} else {
-			raw[offset] = byte(this.CommonAddr)
+			dst[offset] = byte(this.CommonAddr)
 		}
 	} else { // 2
-		raw[offset] = byte(this.CommonAddr)
+		dst[offset] = byte(this.CommonAddr)
 		offset++
-		raw[offset] = byte(this.CommonAddr >> 8)
+		dst[offset] = byte(this.CommonAddr >> 8)
+	}
+	copy(dst[this.IdentifierSize():n], this.InfoObj)
+	return n, nil
+}
+
+// MarshalBinary honors the encoding.BinaryMarshaler interface. It is a
+// thin wrapper around MarshalInto backed by the bootstrap array; prefer
+// MarshalInto when a destination buffer is already at hand.
+func (this *ASDU) MarshalBinary() (data []byte, err error) {
+	n, err := this.MarshalInto(this.bootstrap[:cap(this.bootstrap)])
+	if err != nil {
+		return nil, err
 	}
-	return raw, nil
+	return this.bootstrap[:n], nil
 }
 
 // UnmarshalBinary honors the encoding.BinaryUnmarshaler interface.
@@ -309,10 +444,10 @@ func (u *ASDU) UnmarshalBinary(data []byte) error {
 	u.Variable = ParseVariableStruct(data[1])
 	var size int
 	// read the variable structure qualifier
-	if u.Variable.IsSequence {
-		size = u.InfoObjAddrSize + (int(u.Variable.Number&0x7f) * objSize)
+	if u.Variable.Sequence() {
+		size = u.InfoObjAddrSize + (u.Variable.Count() * objSize)
 	} else {
-		size = int(u.Variable.Number) * (u.InfoObjAddrSize + objSize)
+		size = u.Variable.Count() * (u.InfoObjAddrSize + objSize)
 	}
 
 	switch {
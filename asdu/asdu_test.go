@@ -0,0 +1,125 @@
+package asdu
+
+import "testing"
+
+func TestASDU_Objects(t *testing.T) {
+	u := NewEmptyASDU(ParamsNarrow)
+	u.Type = M_SP_NA_1
+	u.Coa = CauseOfTransmission{Cause: Spont}
+	u.CommonAddr = 1
+
+	if err := u.AppendInfoObjAddr(1); err != nil {
+		t.Fatalf("AppendInfoObjAddr(1): %v", err)
+	}
+	u.InfoObj = append(u.InfoObj, 0x01)
+	if err := u.AppendInfoObjAddr(2); err != nil {
+		t.Fatalf("AppendInfoObjAddr(2): %v", err)
+	}
+	u.InfoObj = append(u.InfoObj, 0x00)
+
+	objs, err := u.Objects()
+	if err != nil {
+		t.Fatalf("Objects: %v", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("got %d objects, want 2", len(objs))
+	}
+	if objs[0].Addr != 1 || objs[0].Raw[0] != 0x01 {
+		t.Errorf("objs[0] = %+v, want addr 1 raw [0x01]", objs[0])
+	}
+	if objs[1].Addr != 2 || objs[1].Raw[0] != 0x00 {
+		t.Errorf("objs[1] = %+v, want addr 2 raw [0x00]", objs[1])
+	}
+}
+
+func TestASDU_Objects_Sequence(t *testing.T) {
+	u := NewEmptyASDU(ParamsNarrow)
+	u.Type = M_SP_NA_1
+	u.Coa = CauseOfTransmission{Cause: Spont}
+	u.CommonAddr = 1
+	u.Variable.SetSequence(true)
+
+	if err := u.AppendInfoObjAddr(10); err != nil {
+		t.Fatalf("AppendInfoObjAddr: %v", err)
+	}
+	u.InfoObj = append(u.InfoObj, 0x01, 0x00)
+	// The address append above only counted the first element; the
+	// second element of the sequence still needs to be counted.
+	if err := u.Variable.incCount(); err != nil {
+		t.Fatalf("incCount: %v", err)
+	}
+
+	objs, err := u.Objects()
+	if err != nil {
+		t.Fatalf("Objects: %v", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("got %d objects, want 2", len(objs))
+	}
+	if objs[0].Addr != 10 || objs[1].Addr != 11 {
+		t.Errorf("addrs = %d, %d; want 10, 11", objs[0].Addr, objs[1].Addr)
+	}
+}
+
+func TestASDU_Objects_Truncated(t *testing.T) {
+	u := NewEmptyASDU(ParamsNarrow)
+	u.Type = M_SP_NA_1
+	u.Coa = CauseOfTransmission{Cause: Spont}
+	u.CommonAddr = 1
+
+	if err := u.AppendInfoObjAddr(1); err != nil {
+		t.Fatalf("AppendInfoObjAddr: %v", err)
+	}
+	// No element byte follows the address: Objects must report the
+	// truncation rather than panic or silently return a short object.
+	if _, err := u.Objects(); err == nil {
+		t.Fatal("Objects: want error on truncated InfoObj, got nil")
+	}
+}
+
+func TestASDU_String(t *testing.T) {
+	u := NewEmptyASDU(ParamsNarrow)
+	u.Type = M_SP_NA_1
+	u.Coa = CauseOfTransmission{Cause: Spont}
+	u.CommonAddr = 1
+
+	if err := u.AppendInfoObjAddr(1); err != nil {
+		t.Fatalf("AppendInfoObjAddr: %v", err)
+	}
+	u.InfoObj = append(u.InfoObj, 0x01)
+
+	got := u.String()
+	if got == "" {
+		t.Fatal("String() returned an empty string")
+	}
+}
+
+func TestASDU_RespondAndReply(t *testing.T) {
+	in := NewASDU(ParamsNarrow, Identifier{
+		Type:       M_SP_NA_1,
+		Coa:        CauseOfTransmission{Cause: Act | TestFlag},
+		CommonAddr: 1,
+		OrigAddr:   2,
+	})
+	if err := in.AppendInfoObjAddr(5); err != nil {
+		t.Fatalf("AppendInfoObjAddr: %v", err)
+	}
+	in.InfoObj = append(in.InfoObj, 0x01)
+
+	resp := in.Respond(M_SP_NA_1, ActCon)
+	if resp.CommonAddr != in.CommonAddr || resp.OrigAddr != in.OrigAddr {
+		t.Errorf("Respond did not carry over CommonAddr/OrigAddr")
+	}
+	if resp.Coa.Cause&TestFlag == 0 {
+		t.Errorf("Respond did not preserve TestFlag")
+	}
+
+	reply := in.Reply(ActCon)
+	if len(reply.InfoObj) != len(in.InfoObj) {
+		t.Errorf("Reply InfoObj length = %d, want %d", len(reply.InfoObj), len(in.InfoObj))
+	}
+	reply.InfoObj[0] = 0xff
+	if in.InfoObj[0] == 0xff {
+		t.Errorf("Reply did not deep-copy InfoObj")
+	}
+}
@@ -0,0 +1,175 @@
+package asdu
+
+// File transfer type identifications, companion standard 101,
+// subclause 7.4.11 and its type ID table.
+const (
+	F_FR_NA_1 TypeID = 120 + iota // file ready
+	F_SR_NA_1                     // section ready
+	F_SC_NA_1                     // call directory, select file, call file, call section
+	F_LS_NA_1                     // last section, last segment
+	F_AF_NA_1                     // ack file, ack section
+	F_SG_NA_1                     // segment
+	F_DR_TA_1                     // directory
+	// 127 is reserved by the standard.
+)
+
+// NameOfFile identifies a file within a station, companion standard 101,
+// subclause 7.2.6.35.
+type NameOfFile uint16
+
+// LengthOfFile is a file size in bytes, companion standard 101,
+// subclause 7.2.6.36. Only the low 24 bits are significant on the wire.
+type LengthOfFile uint32
+
+// NameOfSection identifies a section within a file, companion standard
+// 101, subclause 7.2.6.36.
+type NameOfSection uint8
+
+// Status of file (SOF) / status of section (SOS) octet flags, companion
+// standard 101, subclause 7.2.6.34.
+const (
+	// SOF/SOS status of transfer, bits 0-4.
+	FileStatusMask = 0x1f
+	// FOR: name of file/section follows (directory response).
+	FileStatusFOR = 1 << 5
+	// FA: file/section transfer is active.
+	FileStatusFA = 1 << 6
+	// LFD: last file of directory / last section of file.
+	FileStatusLFD = 1 << 7
+)
+
+// Each Append* helper below appends exactly one complete information
+// object (address plus its fields) and advances the variable structure
+// qualifier's count by one, the same guarantee AppendInfoObjAddr gives
+// for single-field objects elsewhere in the package. They write the
+// address through appendInfoObjAddrNoCount rather than the exported
+// AppendInfoObjAddr so the count is bumped once per object instead of
+// once per field.
+
+// AppendFileCall appends a call-directory/select-file/call-file
+// information object (address, file name).
+func (u *ASDU) AppendFileCall(ioa InfoObjAddr, name NameOfFile) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	return u.Variable.incCount()
+}
+
+// AppendFileReady appends a file-ready information object (address, file
+// name, file length, status of file).
+func (u *ASDU) AppendFileReady(ioa InfoObjAddr, name NameOfFile, length LengthOfFile, status byte) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	if err := u.appendLengthOfFile(length); err != nil {
+		return err
+	}
+	u.InfoObj = append(u.InfoObj, status)
+	return u.Variable.incCount()
+}
+
+// AppendSectionCall appends a call-section information object (address,
+// file name, section name).
+func (u *ASDU) AppendSectionCall(ioa InfoObjAddr, name NameOfFile, section NameOfSection) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	u.InfoObj = append(u.InfoObj, byte(section))
+	return u.Variable.incCount()
+}
+
+// AppendSectionReady appends a section-ready information object (address,
+// file name, section name, section length, status of section).
+func (u *ASDU) AppendSectionReady(ioa InfoObjAddr, name NameOfFile, section NameOfSection, length LengthOfFile, status byte) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	u.InfoObj = append(u.InfoObj, byte(section))
+	if err := u.appendLengthOfFile(length); err != nil {
+		return err
+	}
+	u.InfoObj = append(u.InfoObj, status)
+	return u.Variable.incCount()
+}
+
+// AppendSegment appends one F_SG_NA_1 segment (address, file name,
+// section name, raw segment bytes).
+func (u *ASDU) AppendSegment(ioa InfoObjAddr, name NameOfFile, section NameOfSection, data []byte) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	u.InfoObj = append(u.InfoObj, byte(section))
+	u.InfoObj = append(u.InfoObj, data...)
+	return u.Variable.incCount()
+}
+
+// AppendLastSection appends the F_LS_NA_1 terminator of a section's
+// segment stream (address, file name, section name, checksum, status of
+// section).
+func (u *ASDU) AppendLastSection(ioa InfoObjAddr, name NameOfFile, section NameOfSection, checksum, status byte) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	u.InfoObj = append(u.InfoObj, byte(section), checksum, status)
+	return u.Variable.incCount()
+}
+
+// AppendAckSection appends a per-section acknowledgement (address, file
+// name, section name, status of section; nonzero status signals a
+// negative acknowledgement, e.g. a checksum mismatch).
+func (u *ASDU) AppendAckSection(ioa InfoObjAddr, name NameOfFile, section NameOfSection, status byte) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	u.InfoObj = append(u.InfoObj, byte(section), status)
+	return u.Variable.incCount()
+}
+
+// AppendAckFile appends the file-transfer termination acknowledgement
+// (address, file name, status of file).
+func (u *ASDU) AppendAckFile(ioa InfoObjAddr, name NameOfFile, status byte) error {
+	if err := u.appendInfoObjAddrNoCount(ioa); err != nil {
+		return err
+	}
+	u.appendNameOfFile(name)
+	u.InfoObj = append(u.InfoObj, status)
+	return u.Variable.incCount()
+}
+
+func (u *ASDU) appendNameOfFile(name NameOfFile) {
+	u.InfoObj = append(u.InfoObj, byte(name), byte(name>>8))
+}
+
+// appendLengthOfFile appends a file or section length. Only the low 24
+// bits are significant on the wire.
+func (u *ASDU) appendLengthOfFile(length LengthOfFile) error {
+	if length > 0xffffff {
+		return errInfoObjIndexFit
+	}
+	u.InfoObj = append(u.InfoObj, byte(length), byte(length>>8), byte(length>>16))
+	return nil
+}
+
+// ParseNameOfFile decodes a file name from the head of buf.
+func ParseNameOfFile(buf []byte) (NameOfFile, error) {
+	if len(buf) < 2 {
+		return 0, errParam
+	}
+	return NameOfFile(buf[0]) | NameOfFile(buf[1])<<8, nil
+}
+
+// ParseLengthOfFile decodes a 3-byte file or section length from the
+// head of buf.
+func ParseLengthOfFile(buf []byte) (LengthOfFile, error) {
+	if len(buf) < 3 {
+		return 0, errParam
+	}
+	return LengthOfFile(buf[0]) | LengthOfFile(buf[1])<<8 | LengthOfFile(buf[2])<<16, nil
+}
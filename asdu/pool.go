@@ -0,0 +1,43 @@
+package asdu
+
+import "sync"
+
+// Pool recycles ASDU values, avoiding the per-message allocation that
+// NewEmptyASDU otherwise pays under sustained 104 traffic. The zero value
+// is ready to use; NewPool returns the same. CS104 server/client loops
+// should Acquire on receive/send and Release once a message is fully
+// handled.
+//
+// This tree has no CS104 package yet, so nothing actually wires Pool
+// into a server/client send path; Acquire/Release are exercised here
+// only by TestPool_AcquireRelease and BenchmarkMarshalInto_Pool. Wiring them into a real connection
+// loop is left to whoever adds that package.
+type Pool struct {
+	pool sync.Pool
+}
+
+// NewPool returns a ready-to-use Pool.
+func NewPool() *Pool {
+	return &Pool{}
+}
+
+// Acquire returns an ASDU configured with params, either recycled from
+// the pool or freshly allocated as NewEmptyASDU would.
+func (p *Pool) Acquire(params *Params) *ASDU {
+	if v := p.pool.Get(); v != nil {
+		a := v.(*ASDU)
+		a.Params = params
+		lenDUI := a.IdentifierSize()
+		a.InfoObj = a.bootstrap[lenDUI:lenDUI]
+		return a
+	}
+	return NewEmptyASDU(params)
+}
+
+// Release resets u and returns it to the pool. The caller must not use u
+// again afterwards.
+func (p *Pool) Release(u *ASDU) {
+	u.Identifier = Identifier{}
+	u.InfoObj = nil
+	p.pool.Put(u)
+}
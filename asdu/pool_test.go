@@ -0,0 +1,71 @@
+package asdu
+
+import "testing"
+
+func TestPool_AcquireRelease(t *testing.T) {
+	p := NewPool()
+	u := p.Acquire(ParamsNarrow)
+	u.Type = M_SP_NA_1
+	u.Coa = CauseOfTransmission{Cause: Spont}
+	u.CommonAddr = 1
+	if err := u.AppendInfoObjAddr(1); err != nil {
+		t.Fatalf("AppendInfoObjAddr: %v", err)
+	}
+	u.InfoObj = append(u.InfoObj, 0x01)
+
+	p.Release(u)
+
+	u2 := p.Acquire(ParamsNarrow)
+	if u2 != u {
+		t.Fatalf("Acquire after Release did not return the recycled ASDU")
+	}
+	if len(u2.InfoObj) != 0 {
+		t.Errorf("recycled ASDU InfoObj len = %d, want 0", len(u2.InfoObj))
+	}
+	if cap(u2.InfoObj) == 0 {
+		t.Errorf("recycled ASDU InfoObj lost its bootstrap-backed capacity")
+	}
+}
+
+func buildBenchASDU(u *ASDU) {
+	u.Type = M_SP_NA_1
+	u.Coa = CauseOfTransmission{Cause: Spont}
+	u.CommonAddr = 1
+	_ = u.AppendInfoObjAddr(1)
+	u.InfoObj = append(u.InfoObj, 0x01)
+}
+
+// BenchmarkMarshalInto_Pool demonstrates the alloc/op drop MarshalInto
+// gives when paired with Pool: Acquire/Release recycle both the ASDU and
+// its bootstrap array, and MarshalInto writes into a caller-owned buffer,
+// so a steady-state send loop built this way need not allocate per
+// message.
+func BenchmarkMarshalInto_Pool(b *testing.B) {
+	p := NewPool()
+	buf := make([]byte, ASDUSizeMax)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		u := p.Acquire(ParamsNarrow)
+		buildBenchASDU(u)
+		if _, err := u.MarshalInto(buf); err != nil {
+			b.Fatalf("MarshalInto: %v", err)
+		}
+		p.Release(u)
+	}
+}
+
+// BenchmarkMarshalBinary_NoPool is the baseline this package's zero-
+// allocation path improves on: a fresh ASDU per message and the
+// MarshalBinary convenience wrapper.
+func BenchmarkMarshalBinary_NoPool(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		u := NewEmptyASDU(ParamsNarrow)
+		buildBenchASDU(u)
+		if _, err := u.MarshalBinary(); err != nil {
+			b.Fatalf("MarshalBinary: %v", err)
+		}
+	}
+}
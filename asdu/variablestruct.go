@@ -0,0 +1,97 @@
+package asdu
+
+import (
+	"errors"
+	"log"
+)
+
+// errVarStructCountFit reports that the variable structure qualifier's
+// recorded count does not match the number of information objects
+// actually present in InfoObj.
+var errVarStructCountFit = errors.New("asdu: variable structure qualifier count does not match InfoObj")
+
+// VarStructQual is the variable structure qualifier: a single byte whose
+// bit 7 carries the SQ (sequence) flag and whose bits 0-6 carry the number
+// of information objects or elements. See companion standard 101,
+// subclause 7.2.2.
+type VarStructQual uint8
+
+// VariableStruct is kept as a type alias so code that only names the
+// type still compiles.
+//
+// Deprecated: use VarStructQual. This is not a full compatibility shim:
+// the old VariableStruct was a struct with IsSequence/Number fields, and
+// Go does not let a uint8 expose those as fields. Call sites that read
+// u.Variable.IsSequence or u.Variable.Number as plain field accesses do
+// not compile against VarStructQual and must be migrated to the
+// Sequence()/Count() methods (Number() and IsSequence() exist only as
+// deprecated method-call aliases for code already written against them
+// as methods).
+type VariableStruct = VarStructQual
+
+const (
+	varStructQualSeqFlag   VarStructQual = 0x80
+	varStructQualCountMask VarStructQual = 0x7f
+	varStructQualCountMax                = 0x7f
+)
+
+// ParseVariableStruct decodes a variable structure qualifier byte.
+func ParseVariableStruct(b byte) VarStructQual {
+	return VarStructQual(b)
+}
+
+// SetSequence sets or clears the SQ bit.
+func (v *VarStructQual) SetSequence(seq bool) {
+	if seq {
+		*v |= varStructQualSeqFlag
+	} else {
+		*v &^= varStructQualSeqFlag
+	}
+}
+
+// Sequence reports whether the SQ bit is set.
+func (v VarStructQual) Sequence() bool {
+	return v&varStructQualSeqFlag != 0
+}
+
+// Count returns the number of information objects or elements, 0..127.
+func (v VarStructQual) Count() int {
+	return int(v & varStructQualCountMask)
+}
+
+// setCount overwrites the count bits, leaving the SQ bit untouched.
+func (v *VarStructQual) setCount(n int) error {
+	if n < 0 || n > varStructQualCountMax {
+		return errInfoObjIndexFit
+	}
+	*v = (*v &^ varStructQualCountMask) | VarStructQual(n)
+	return nil
+}
+
+// incCount increments the count bits by one.
+func (v *VarStructQual) incCount() error {
+	return v.setCount(v.Count() + 1)
+}
+
+// Value returns the raw byte encoding of the qualifier.
+func (v VarStructQual) Value() byte {
+	return byte(v)
+}
+
+// Number returns the element count.
+//
+// Deprecated: use Count. Kept for code that already calls Number() as a
+// method; the old field access u.Variable.Number does not compile as a
+// byte against VarStructQual. Logs so the remaining call sites can be
+// found and migrated.
+func (v VarStructQual) Number() byte {
+	log.Println("asdu: VarStructQual.Number is deprecated, use Count")
+	return byte(v.Count())
+}
+
+// IsSequence reports the SQ bit.
+//
+// Deprecated: use Sequence.
+func (v VarStructQual) IsSequence() bool {
+	return v.Sequence()
+}
@@ -0,0 +1,279 @@
+package filetransfer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/yidigo/go-iecp5/asdu"
+)
+
+// errAborted is returned when the peer answers with a negative
+// acknowledgement instead of continuing the transfer.
+var errAborted = errors.New("filetransfer: transfer aborted by peer")
+
+// errTooManySections is returned when a file needs more sections than
+// NameOfSection, a single byte, can address.
+var errTooManySections = errors.New("filetransfer: file needs more than 255 sections")
+
+// Transport moves file-transfer ASDUs to and from the peer. Send and
+// Receive are independent: segments stream via repeated Sends with no
+// reply expected per segment, while a request such as call-file is a
+// Send followed by a separate Receive for its answer. A CS104 connection
+// satisfies this by wiring Send to its send path and Receive to a
+// filtered read off its receive loop; this package does not own the
+// socket, retry timer or APDU framing itself.
+type Transport interface {
+	// Send transmits asd to the peer.
+	Send(ctx context.Context, asd *asdu.ASDU) error
+	// Receive blocks for the next file-transfer ASDU from the peer,
+	// honoring ctx's deadline.
+	Receive(ctx context.Context) (*asdu.ASDU, error)
+}
+
+// ProgressFunc reports bytes transferred so far out of total; total is 0
+// if the file length is not yet known.
+type ProgressFunc func(transferred, total asdu.LengthOfFile)
+
+// Client downloads files from a station, driving the file-ready ->
+// call-file -> section-ready -> call-section -> segment-stream -> ack
+// procedure of companion standard 101, subclause 7.4.11.
+type Client struct {
+	Params   *asdu.Params
+	Conn     Transport
+	Timeout  time.Duration // per round-trip, mirrors the CS104 t1 default when zero
+	Retries  int           // additional attempts per round-trip after the first
+	Progress ProgressFunc
+}
+
+// NewClient returns a Client bound to conn, with CS104-like defaults.
+func NewClient(params *asdu.Params, conn Transport) *Client {
+	return &Client{Params: params, Conn: conn, Timeout: 15 * time.Second, Retries: 2}
+}
+
+// Download requests name from ca/ioa and returns its content, streaming
+// segments as they arrive rather than buffering the whole file.
+func (c *Client) Download(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.download(ctx, ca, ioa, name, pw))
+	}()
+	return pr, nil
+}
+
+func (c *Client) download(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, dst io.Writer) error {
+	fileReady, err := c.callFile(ctx, ca, ioa, name)
+	if err != nil {
+		return err
+	}
+	total, _, err := parseFileReady(c.Params, fileReady)
+	if err != nil {
+		return err
+	}
+
+	var transferred asdu.LengthOfFile
+	for section := asdu.NameOfSection(1); ; section++ {
+		sectionReady, err := c.callSection(ctx, ca, ioa, name, section)
+		if err != nil {
+			return err
+		}
+		sectionLen, status, err := parseSectionReady(c.Params, sectionReady)
+		if err != nil {
+			return err
+		}
+		lastSection := status&asdu.FileStatusLFD != 0
+
+		wantSum, n, gotSum, err := c.receiveSection(ctx, dst, section)
+		if err != nil {
+			return err
+		}
+		transferred += asdu.LengthOfFile(n)
+		if c.Progress != nil {
+			c.Progress(transferred, total)
+		}
+
+		ok := n == int(sectionLen) && gotSum == wantSum
+		if err := c.ackSection(ctx, ca, ioa, name, section, ok); err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+		if lastSection {
+			break
+		}
+	}
+	return c.ackFile(ctx, ca, ioa, name)
+}
+
+// callFile sends the call-file activation and returns the F_FR_NA_1
+// file-ready response.
+func (c *Client) callFile(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile) (*asdu.ASDU, error) {
+	req := asdu.NewASDU(c.Params, asdu.Identifier{
+		Type:       asdu.F_SC_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Act},
+	})
+	if err := req.AppendFileCall(ioa, name); err != nil {
+		return nil, err
+	}
+	return c.exchange(ctx, req)
+}
+
+// callSection requests the given section.
+func (c *Client) callSection(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, section asdu.NameOfSection) (*asdu.ASDU, error) {
+	req := asdu.NewASDU(c.Params, asdu.Identifier{
+		Type:       asdu.F_SC_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Req},
+	})
+	if err := req.AppendSectionCall(ioa, name, section); err != nil {
+		return nil, err
+	}
+	return c.exchange(ctx, req)
+}
+
+// receiveSection reads F_SG_NA_1 segments for section until the
+// terminating F_LS_NA_1, writing payload bytes to dst. It returns the
+// checksum the sender computed over the section (wantSum, carried by
+// F_LS_NA_1), the number of payload bytes written, and the checksum the
+// client itself computed over those same bytes (gotSum).
+func (c *Client) receiveSection(ctx context.Context, dst io.Writer, section asdu.NameOfSection) (wantSum byte, n int, gotSum byte, err error) {
+	addrSize := c.Params.InfoObjAddrSize
+	const headFields = 2 + 1 // NameOfFile + NameOfSection
+	for {
+		seg, err := c.recv(ctx)
+		if err != nil {
+			return 0, n, gotSum, err
+		}
+		if len(seg.InfoObj) < addrSize+headFields {
+			return 0, n, gotSum, errAborted
+		}
+		switch seg.Type {
+		case asdu.F_SG_NA_1:
+			payload := seg.InfoObj[addrSize+headFields:]
+			if _, err := dst.Write(payload); err != nil {
+				return 0, n, gotSum, err
+			}
+			gotSum += Checksum(payload)
+			n += len(payload)
+		case asdu.F_LS_NA_1:
+			// AppendLastSection additionally writes a checksum byte
+			// past the common head fields; guard that byte on its own
+			// so a truncated F_LS_NA_1 errors instead of panicking.
+			if len(seg.InfoObj) < addrSize+headFields+1 {
+				return 0, n, gotSum, errAborted
+			}
+			return seg.InfoObj[addrSize+headFields], n, gotSum, nil
+		default:
+			return 0, n, gotSum, errAborted
+		}
+	}
+}
+
+func (c *Client) ackSection(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, section asdu.NameOfSection, ok bool) error {
+	req := asdu.NewASDU(c.Params, asdu.Identifier{
+		Type:       asdu.F_AF_NA_1,
+		CommonAddr: ca,
+		Coa:        ackCause(ok),
+	})
+	status := byte(0)
+	if !ok {
+		status = 1
+	}
+	if err := req.AppendAckSection(ioa, name, section, status); err != nil {
+		return err
+	}
+	// The server answers an acknowledgement with nothing, so this is a
+	// plain Send rather than an exchange.
+	cctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+	return c.Conn.Send(cctx, req)
+}
+
+func (c *Client) ackFile(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile) error {
+	req := asdu.NewASDU(c.Params, asdu.Identifier{
+		Type:       asdu.F_AF_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.ActTerm},
+	})
+	if err := req.AppendAckFile(ioa, name, 0); err != nil {
+		return err
+	}
+	cctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+	return c.Conn.Send(cctx, req)
+}
+
+// ackCause picks the cause of transmission that marks an acknowledgement
+// as positive or negative.
+func ackCause(ok bool) asdu.CauseOfTransmission {
+	if ok {
+		return asdu.CauseOfTransmission{Cause: asdu.Act}
+	}
+	return asdu.CauseOfTransmission{Cause: asdu.Deact}
+}
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return 15 * time.Second
+}
+
+// exchange sends req and retries the round-trip up to c.Retries times on
+// any error, a timeout included, mirroring the CS104 t1 retransmission
+// behaviour.
+func (c *Client) exchange(ctx context.Context, req *asdu.ASDU) (*asdu.ASDU, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		cctx, cancel := context.WithTimeout(ctx, c.timeout())
+		resp, err := c.roundTrip(cctx, req)
+		cancel()
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (c *Client) roundTrip(ctx context.Context, req *asdu.ASDU) (*asdu.ASDU, error) {
+	if err := c.Conn.Send(ctx, req); err != nil {
+		return nil, err
+	}
+	return c.Conn.Receive(ctx)
+}
+
+func (c *Client) recv(ctx context.Context) (*asdu.ASDU, error) {
+	cctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+	return c.Conn.Receive(cctx)
+}
+
+func parseFileReady(p *asdu.Params, fileReady *asdu.ASDU) (length asdu.LengthOfFile, status byte, err error) {
+	addrSize := p.InfoObjAddrSize
+	const fields = 2 + 3 + 1 // NameOfFile + LengthOfFile + status
+	if len(fileReady.InfoObj) < addrSize+fields {
+		return 0, 0, errAborted
+	}
+	length, err = asdu.ParseLengthOfFile(fileReady.InfoObj[addrSize+2:])
+	if err != nil {
+		return 0, 0, err
+	}
+	return length, fileReady.InfoObj[addrSize+fields-1], nil
+}
+
+func parseSectionReady(p *asdu.Params, sectionReady *asdu.ASDU) (length asdu.LengthOfFile, status byte, err error) {
+	addrSize := p.InfoObjAddrSize
+	const fields = 2 + 1 + 3 + 1 // NameOfFile + NameOfSection + LengthOfFile + status
+	if len(sectionReady.InfoObj) < addrSize+fields {
+		return 0, 0, errAborted
+	}
+	length, err = asdu.ParseLengthOfFile(sectionReady.InfoObj[addrSize+3:])
+	if err != nil {
+		return 0, 0, err
+	}
+	return length, sectionReady.InfoObj[addrSize+fields-1], nil
+}
@@ -0,0 +1,218 @@
+package filetransfer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/yidigo/go-iecp5/asdu"
+)
+
+// chanTransport is an in-process Transport backed by a pair of channels,
+// letting the conformance test below drive Client and Server against
+// each other without a real CS104 connection.
+type chanTransport struct {
+	send chan<- *asdu.ASDU
+	recv <-chan *asdu.ASDU
+}
+
+func (t *chanTransport) Send(ctx context.Context, asd *asdu.ASDU) error {
+	select {
+	case t.send <- asd:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *chanTransport) Receive(ctx context.Context) (*asdu.ASDU, error) {
+	select {
+	case asd := <-t.recv:
+		return asd, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pipe returns a connected pair of Transports, one for each end.
+func pipe() (client, server Transport) {
+	toServer := make(chan *asdu.ASDU)
+	toClient := make(chan *asdu.ASDU)
+	return &chanTransport{send: toServer, recv: toClient},
+		&chanTransport{send: toClient, recv: toServer}
+}
+
+// staticHandler serves a single fixed file regardless of the requested
+// address or name.
+type staticHandler struct {
+	data []byte
+}
+
+func (h staticHandler) Open(ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile) (io.ReadCloser, asdu.LengthOfFile, error) {
+	return io.NopCloser(bytes.NewReader(h.data)), asdu.LengthOfFile(len(h.data)), nil
+}
+
+// TestClientServer_Conformance drives Client and Server against each
+// other over an in-process Transport with a multi-MB payload, spanning
+// many sections (but staying under the 255-section limit NameOfSection
+// can address) and many segments per section, and checks the downloaded
+// content is byte-for-byte identical to what the server offered.
+func TestClientServer_Conformance(t *testing.T) {
+	data := make([]byte, 200*MaxSectionSize+12345) // ~3 MiB, uneven last section and segment
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	clientConn, serverConn := pipe()
+	server := NewServer(asdu.ParamsNarrow, serverConn)
+	client := NewClient(asdu.ParamsNarrow, clientConn)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(staticHandler{data: data})
+	}()
+
+	rc, err := client.Download(context.Background(), 1, 1, 1)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("reading download: %v", err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if err := <-serveErr; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes, want %d; content mismatch", len(got), len(data))
+	}
+}
+
+// TestClientServer_Conformance_Progress checks that Progress is called
+// with a monotonically increasing transferred count that ends at the
+// full file length.
+func TestClientServer_Conformance_Progress(t *testing.T) {
+	data := make([]byte, MaxSectionSize+1)
+
+	clientConn, serverConn := pipe()
+	server := NewServer(asdu.ParamsNarrow, serverConn)
+	client := NewClient(asdu.ParamsNarrow, clientConn)
+
+	var last asdu.LengthOfFile
+	client.Progress = func(transferred, total asdu.LengthOfFile) {
+		if transferred < last {
+			t.Errorf("Progress went backwards: %d after %d", transferred, last)
+		}
+		last = transferred
+		if total != asdu.LengthOfFile(len(data)) {
+			t.Errorf("Progress total = %d, want %d", total, len(data))
+		}
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(staticHandler{data: data})
+	}()
+
+	rc, err := client.Download(context.Background(), 1, 1, 1)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if _, err := io.ReadAll(rc); err != nil {
+		t.Fatalf("reading download: %v", err)
+	}
+	rc.Close()
+
+	if err := <-serveErr; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if last != asdu.LengthOfFile(len(data)) {
+		t.Errorf("final Progress transferred = %d, want %d", last, len(data))
+	}
+}
+
+// TestServer_TooManySections checks that a file needing more than 255
+// sections - more than NameOfSection, a single byte, can address - is
+// rejected up front instead of silently wrapping the section counter.
+func TestServer_TooManySections(t *testing.T) {
+	data := make([]byte, 256*MaxSectionSize)
+
+	clientConn, serverConn := pipe()
+	server := NewServer(asdu.ParamsNarrow, serverConn)
+
+	req := asdu.NewASDU(asdu.ParamsNarrow, asdu.Identifier{
+		Type:       asdu.F_SC_NA_1,
+		CommonAddr: 1,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Act},
+	})
+	if err := req.AppendFileCall(1, 1); err != nil {
+		t.Fatalf("AppendFileCall: %v", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(staticHandler{data: data})
+	}()
+
+	if err := clientConn.Send(context.Background(), req); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if err := <-serveErr; err != errTooManySections {
+		t.Fatalf("Serve err = %v, want errTooManySections", err)
+	}
+}
+
+// TestServer_ExpectSectionCall_Mismatch checks that a call-section
+// naming the wrong file/address/section is rejected rather than
+// answered with the server's own next section in sequence.
+func TestServer_ExpectSectionCall_Mismatch(t *testing.T) {
+	data := make([]byte, MaxSectionSize+1) // two sections
+
+	clientConn, serverConn := pipe()
+	server := NewServer(asdu.ParamsNarrow, serverConn)
+
+	callFile := asdu.NewASDU(asdu.ParamsNarrow, asdu.Identifier{
+		Type:       asdu.F_SC_NA_1,
+		CommonAddr: 1,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Act},
+	})
+	if err := callFile.AppendFileCall(1, 1); err != nil {
+		t.Fatalf("AppendFileCall: %v", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(staticHandler{data: data})
+	}()
+
+	ctx := context.Background()
+	if err := clientConn.Send(ctx, callFile); err != nil {
+		t.Fatalf("Send call-file: %v", err)
+	}
+	if _, err := clientConn.Receive(ctx); err != nil { // file-ready
+		t.Fatalf("Receive file-ready: %v", err)
+	}
+
+	// Call for section 2 when the server expects a call for section 1.
+	wrongSection := asdu.NewASDU(asdu.ParamsNarrow, asdu.Identifier{
+		Type:       asdu.F_SC_NA_1,
+		CommonAddr: 1,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Req},
+	})
+	if err := wrongSection.AppendSectionCall(1, 1, 2); err != nil {
+		t.Fatalf("AppendSectionCall: %v", err)
+	}
+	if err := clientConn.Send(ctx, wrongSection); err != nil {
+		t.Fatalf("Send call-section: %v", err)
+	}
+
+	if err := <-serveErr; err != errAborted {
+		t.Fatalf("Serve err = %v, want errAborted", err)
+	}
+}
@@ -0,0 +1,24 @@
+// Package filetransfer implements the file transfer procedures of IEC
+// 60870-5-101, companion standard subclause 7.4.11 (F_FR_NA_1,
+// F_SR_NA_1, F_SC_NA_1, F_LS_NA_1, F_AF_NA_1, F_SG_NA_1, F_DR_TA_1), on
+// top of the asdu package.
+//
+// This package covers the parts of the procedure that are independent
+// of any particular transport: encoding the file/section metadata
+// octets, splitting a payload into segments, and computing the section
+// checksum. Client and Server drive the file-ready -> call-file ->
+// section-ready -> call-section -> segment-stream -> ack state machine
+// through the Transport interface, so they can sit on top of whatever
+// CS104 connection type ends up in this tree; this package does not
+// itself own a socket, retry timer or APDU framing, since no CS104
+// client/server implementation exists yet alongside it to bind against.
+//
+// Client.Download retries each round-trip with the CS104 t1-style
+// timeout and streams section payloads to its caller as they arrive.
+// Server.Serve answers a single transfer synchronously and reads the
+// handler's content fully into memory before segmenting it; once a real
+// CS104 Transport exists, pipelining multiple concurrent transfers and
+// streaming large files without buffering is left to that integration.
+// The conformance test drives Client and Server against each other over
+// an in-process Transport, so it does not need a real CS104 connection.
+package filetransfer
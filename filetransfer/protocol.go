@@ -0,0 +1,51 @@
+package filetransfer
+
+// MaxSegmentSize is the largest number of payload bytes carried by a
+// single F_SG_NA_1 segment, companion standard 101, subclause 7.4.11.
+const MaxSegmentSize = 239
+
+// MaxSectionSize is the largest number of payload bytes grouped into one
+// section. A section is requested and acknowledged once as a whole but
+// streamed as many MaxSegmentSize segments, so a multi-MB file needs one
+// call-section/ack round trip per MaxSectionSize bytes rather than one
+// per MaxSegmentSize bytes.
+const MaxSectionSize = 64 * MaxSegmentSize
+
+// Checksum returns the arithmetic sum of segment modulo 256, the check
+// value carried by the F_LS_NA_1 that closes each section.
+func Checksum(segment []byte) byte {
+	var sum byte
+	for _, b := range segment {
+		sum += b
+	}
+	return sum
+}
+
+// Segments splits data into chunks of at most MaxSegmentSize bytes, in
+// the order they must be streamed on the wire.
+func Segments(data []byte) [][]byte {
+	return chunk(data, MaxSegmentSize)
+}
+
+// Sections splits data into chunks of at most MaxSectionSize bytes, in
+// the order they must be offered to the peer as sections; each section
+// is itself streamed as one or more Segments.
+func Sections(data []byte) [][]byte {
+	return chunk(data, MaxSectionSize)
+}
+
+func chunk(data []byte, size int) [][]byte {
+	if len(data) == 0 {
+		return nil
+	}
+	chunks := make([][]byte, 0, (len(data)+size-1)/size)
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+	return chunks
+}
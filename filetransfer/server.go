@@ -0,0 +1,221 @@
+package filetransfer
+
+import (
+	"context"
+	"io"
+
+	"github.com/yidigo/go-iecp5/asdu"
+)
+
+// FileHandler serves file downloads on the station side of a transfer.
+type FileHandler interface {
+	// Open returns the content and size of the named file, or an error
+	// if it does not exist or cannot be read.
+	Open(ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile) (io.ReadCloser, asdu.LengthOfFile, error)
+}
+
+// Server answers file-transfer requests received over conn.
+type Server struct {
+	Params *asdu.Params
+	Conn   Transport
+}
+
+// NewServer returns a Server bound to conn.
+func NewServer(params *asdu.Params, conn Transport) *Server {
+	return &Server{Params: params, Conn: conn}
+}
+
+// Serve answers one call-file request with the handler's content: it
+// sends a file-ready reply, then for every section requested by the
+// peer streams its data as MaxSegmentSize segments terminated by a
+// checksummed F_LS_NA_1, waiting for that section's acknowledgement
+// before moving on, and finally waits for the file acknowledgement that
+// closes out the transfer.
+func (s *Server) Serve(handler FileHandler) error {
+	ctx := context.Background()
+
+	req, err := s.Conn.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	if req.Type != asdu.F_SC_NA_1 || req.Coa.Cause != asdu.Act {
+		return errAborted
+	}
+
+	addrSize := s.Params.InfoObjAddrSize
+	if len(req.InfoObj) < addrSize {
+		return errAborted
+	}
+	ioa, err := req.ParseInfoObjAddr(req.InfoObj)
+	if err != nil {
+		return err
+	}
+	name, err := asdu.ParseNameOfFile(req.InfoObj[addrSize:])
+	if err != nil {
+		return err
+	}
+
+	content, length, err := handler.Open(req.CommonAddr, ioa, name)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	sections := Sections(data)
+	if len(sections) > 255 {
+		// NameOfSection is a single byte (companion standard 101,
+		// subclause 7.2.6.36), so section numbers 1..255 are all it
+		// can address. Reject before sending file-ready rather than
+		// committing the peer to a transfer that can never finish.
+		return errTooManySections
+	}
+
+	if err := s.sendFileReady(ctx, req.CommonAddr, ioa, name, length); err != nil {
+		return err
+	}
+
+	for i, section := range sections {
+		sectionNum := asdu.NameOfSection(i + 1)
+		last := i == len(sections)-1
+
+		if err := s.expectSectionCall(ctx, ioa, name, sectionNum); err != nil {
+			return err
+		}
+		if err := s.sendSectionReady(ctx, req.CommonAddr, ioa, name, sectionNum, asdu.LengthOfFile(len(section)), last); err != nil {
+			return err
+		}
+
+		var checksum byte
+		for _, segment := range Segments(section) {
+			if err := s.sendSegment(ctx, req.CommonAddr, ioa, name, sectionNum, segment); err != nil {
+				return err
+			}
+			checksum += Checksum(segment)
+		}
+		status := byte(0)
+		if last {
+			status = asdu.FileStatusLFD
+		}
+		if err := s.sendLastSection(ctx, req.CommonAddr, ioa, name, sectionNum, checksum, status); err != nil {
+			return err
+		}
+
+		if err := s.expectAckSection(ctx); err != nil {
+			return err
+		}
+	}
+
+	return s.expectAckFile(ctx)
+}
+
+// expectSectionCall receives a call-section request and checks that it
+// actually names the section the server is about to send, rejecting a
+// peer that calls for a different file, address or section number.
+func (s *Server) expectSectionCall(ctx context.Context, ioa asdu.InfoObjAddr, name asdu.NameOfFile, section asdu.NameOfSection) error {
+	req, err := s.Conn.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	if req.Type != asdu.F_SC_NA_1 || req.Coa.Cause != asdu.Req {
+		return errAborted
+	}
+	addrSize := s.Params.InfoObjAddrSize
+	const fields = 2 + 1 // NameOfFile + NameOfSection
+	if len(req.InfoObj) < addrSize+fields {
+		return errAborted
+	}
+	gotIOA, err := req.ParseInfoObjAddr(req.InfoObj)
+	if err != nil {
+		return err
+	}
+	gotName, err := asdu.ParseNameOfFile(req.InfoObj[addrSize:])
+	if err != nil {
+		return err
+	}
+	gotSection := asdu.NameOfSection(req.InfoObj[addrSize+2])
+	if gotIOA != ioa || gotName != name || gotSection != section {
+		return errAborted
+	}
+	return nil
+}
+
+func (s *Server) expectAckSection(ctx context.Context) error {
+	ack, err := s.Conn.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	if ack.Type != asdu.F_AF_NA_1 || ack.Coa.Cause != asdu.Act {
+		return errAborted
+	}
+	return nil
+}
+
+func (s *Server) expectAckFile(ctx context.Context) error {
+	ack, err := s.Conn.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	if ack.Type != asdu.F_AF_NA_1 || ack.Coa.Cause != asdu.ActTerm {
+		return errAborted
+	}
+	return nil
+}
+
+func (s *Server) sendFileReady(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, length asdu.LengthOfFile) error {
+	asd := asdu.NewASDU(s.Params, asdu.Identifier{
+		Type:       asdu.F_FR_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.ActCon},
+	})
+	if err := asd.AppendFileReady(ioa, name, length, 0); err != nil {
+		return err
+	}
+	return s.Conn.Send(ctx, asd)
+}
+
+func (s *Server) sendSectionReady(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, section asdu.NameOfSection, length asdu.LengthOfFile, last bool) error {
+	status := byte(0)
+	if last {
+		status = asdu.FileStatusLFD
+	}
+	asd := asdu.NewASDU(s.Params, asdu.Identifier{
+		Type:       asdu.F_SR_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.ActCon},
+	})
+	if err := asd.AppendSectionReady(ioa, name, section, length, status); err != nil {
+		return err
+	}
+	return s.Conn.Send(ctx, asd)
+}
+
+// sendSegment streams one segment without waiting for a reply: the peer
+// only acknowledges once, after the section's closing F_LS_NA_1.
+func (s *Server) sendSegment(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, section asdu.NameOfSection, segment []byte) error {
+	asd := asdu.NewASDU(s.Params, asdu.Identifier{
+		Type:       asdu.F_SG_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Spont},
+	})
+	if err := asd.AppendSegment(ioa, name, section, segment); err != nil {
+		return err
+	}
+	return s.Conn.Send(ctx, asd)
+}
+
+func (s *Server) sendLastSection(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr, name asdu.NameOfFile, section asdu.NameOfSection, checksum, status byte) error {
+	asd := asdu.NewASDU(s.Params, asdu.Identifier{
+		Type:       asdu.F_LS_NA_1,
+		CommonAddr: ca,
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Spont},
+	})
+	if err := asd.AppendLastSection(ioa, name, section, checksum, status); err != nil {
+		return err
+	}
+	return s.Conn.Send(ctx, asd)
+}